@@ -18,22 +18,94 @@ package create
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	batchv2alpha1 "k8s.io/api/batch/v2alpha1"
 	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/discovery"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
 	batchv1beta1client "k8s.io/client-go/kubernetes/typed/batch/v1beta1"
+	batchv2alpha1client "k8s.io/client-go/kubernetes/typed/batch/v2alpha1"
+	"k8s.io/kubectl/pkg/cmd/create/cron"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/scheme"
 	"k8s.io/kubectl/pkg/util/templates"
 )
 
+// concurrencyPolicies are the values accepted by --concurrency-policy, mirroring
+// batchv1beta1.ConcurrencyPolicy. Forbid is the default: letting runs pile up
+// (Allow) is almost never what users intend, per clusterlint guidance.
+var concurrencyPolicies = sets.NewString(
+	string(batchv1beta1.AllowConcurrent),
+	string(batchv1beta1.ForbidConcurrent),
+	string(batchv1beta1.ReplaceConcurrent),
+)
+
+// cronJobAPIVersionPrecedence lists the CronJob-serving group versions this
+// command knows how to target, in the order they should be preferred when
+// negotiating with a server that serves more than one of them.
+var cronJobAPIVersionPrecedence = []schema.GroupVersion{
+	batchv1.SchemeGroupVersion,
+	batchv1beta1.SchemeGroupVersion,
+	batchv2alpha1.SchemeGroupVersion,
+}
+
+// negotiateCronJobAPIVersion picks the CronJob group version to create against.
+// If apiVersion is non-empty it is used verbatim (after validating it is one
+// this command supports); otherwise the discovery client is asked which batch
+// versions the server serves and the most preferred one is chosen.
+func negotiateCronJobAPIVersion(discoveryClient discovery.DiscoveryInterface, apiVersion string) (schema.GroupVersion, error) {
+	if len(apiVersion) > 0 {
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return schema.GroupVersion{}, fmt.Errorf("invalid --api-version %q: %v", apiVersion, err)
+		}
+		for _, supported := range cronJobAPIVersionPrecedence {
+			if gv == supported {
+				return gv, nil
+			}
+		}
+		return schema.GroupVersion{}, fmt.Errorf("--api-version must be one of batch/v1, batch/v1beta1 or batch/v2alpha1, got %q", apiVersion)
+	}
+
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return schema.GroupVersion{}, fmt.Errorf("failed to discover the CronJob API version served by the server: %v", err)
+	}
+	served := sets.NewString()
+	for _, group := range groups.Groups {
+		if group.Name != batchv1.GroupName {
+			continue
+		}
+		for _, version := range group.Versions {
+			served.Insert(version.Version)
+		}
+	}
+	for _, supported := range cronJobAPIVersionPrecedence {
+		if served.Has(supported.Version) {
+			return supported, nil
+		}
+	}
+	seen := "none"
+	if served.Len() > 0 {
+		seen = strings.Join(served.List(), ", ")
+	}
+	return schema.GroupVersion{}, fmt.Errorf("server does not serve any of the known CronJob API versions (batch/v1, batch/v1beta1, batch/v2alpha1); batch group versions seen: %s", seen)
+}
+
 var (
 	cronjobLong = templates.LongDesc(`
 		Create a cronjob with the specified name.`)
@@ -60,11 +132,52 @@ type CreateCronJobOptions struct {
 	Command  []string
 	Restart  string
 
-	Namespace string
-	Client    batchv1beta1client.BatchV1beta1Interface
-	DryRun    bool
-	Builder   *resource.Builder
-	Cmd       *cobra.Command
+	// FromPod and FromDeployment are mutually exclusive; when set, the
+	// container(s) come from the referenced workload's pod template instead
+	// of being assembled from --image/--command/--restart.
+	FromPod        string
+	FromDeployment string
+
+	ValidateSchedule bool
+	PreviewNext      int
+
+	Env            []string
+	EnvFrom        []string
+	Volumes        []string
+	Mounts         []string
+	ServiceAccount string
+	NodeSelector   []string
+	Labels         string
+	Annotations    string
+	Resources      string
+	Sidecars       []string
+	InitContainers []string
+
+	ConcurrencyPolicy          string
+	Suspend                    bool
+	TimeZone                   string
+	StartingDeadlineSeconds    int64
+	SuccessfulJobsHistoryLimit int32
+	FailedJobsHistoryLimit     int32
+	BackoffLimit               int32
+	ActiveDeadlineSeconds      int64
+	Parallelism                int32
+	Completions                int32
+
+	// APIVersion forces the group/version to create against (--api-version).
+	// When empty, the version is negotiated against the server in Complete.
+	APIVersion string
+	// NegotiatedVersion is the CronJob-serving group version that was chosen,
+	// either because it was forced via APIVersion or discovered from the server.
+	NegotiatedVersion schema.GroupVersion
+
+	Namespace      string
+	ClientV1       batchv1client.BatchV1Interface
+	ClientV1beta1  batchv1beta1client.BatchV1beta1Interface
+	ClientV2alpha1 batchv2alpha1client.BatchV2alpha1Interface
+	DryRun         bool
+	Builder        *resource.Builder
+	Cmd            *cobra.Command
 
 	genericclioptions.IOStreams
 }
@@ -100,6 +213,32 @@ func NewCmdCreateCronJob(f cmdutil.Factory, ioStreams genericclioptions.IOStream
 	cmd.Flags().StringVar(&o.Image, "image", o.Image, "Image name to run.")
 	cmd.Flags().StringVar(&o.Schedule, "schedule", o.Schedule, "A schedule in the Cron format the job should be run with.")
 	cmd.Flags().StringVar(&o.Restart, "restart", o.Restart, "job's restart policy. supported values: OnFailure, Never")
+	cmd.Flags().StringVar(&o.ConcurrencyPolicy, "concurrency-policy", o.ConcurrencyPolicy, "Specifies how to treat concurrent executions of a job that is created by this cronjob. supported values: Allow, Forbid, Replace")
+	cmd.Flags().BoolVar(&o.Suspend, "suspend", o.Suspend, "If set, the cronjob is suspended immediately after creation.")
+	cmd.Flags().StringVar(&o.TimeZone, "timezone", o.TimeZone, "The time zone name to run the cronjob in, e.g. 'America/New_York'. Set as spec.timeZone for batch/v1; for batch/v1beta1 and batch/v2alpha1 it is added to --schedule as a CRON_TZ= prefix.")
+	cmd.Flags().Int64Var(&o.StartingDeadlineSeconds, "starting-deadline-seconds", o.StartingDeadlineSeconds, "The deadline in seconds, relative to the scheduled time, by which a missed job run must be started.")
+	cmd.Flags().Int32Var(&o.SuccessfulJobsHistoryLimit, "successful-jobs-history-limit", o.SuccessfulJobsHistoryLimit, "The number of successful finished jobs to retain.")
+	cmd.Flags().Int32Var(&o.FailedJobsHistoryLimit, "failed-jobs-history-limit", o.FailedJobsHistoryLimit, "The number of failed finished jobs to retain.")
+	cmd.Flags().Int32Var(&o.BackoffLimit, "backoff-limit", o.BackoffLimit, "The number of retries before marking a job run as failed.")
+	cmd.Flags().Int64Var(&o.ActiveDeadlineSeconds, "active-deadline-seconds", o.ActiveDeadlineSeconds, "The duration in seconds relative to the job run's start time that the job may be active before the system tries to terminate it.")
+	cmd.Flags().Int32Var(&o.Parallelism, "parallelism", o.Parallelism, "The maximum number of pods the job run may run concurrently.")
+	cmd.Flags().Int32Var(&o.Completions, "completions", o.Completions, "The number of successful pod completions the job run needs to be considered complete.")
+	cmd.Flags().StringVar(&o.APIVersion, "api-version", o.APIVersion, "Force a specific CronJob group/version (one of batch/v1, batch/v1beta1, batch/v2alpha1) instead of negotiating with the server. Useful with --dry-run=client to pin the output schema.")
+	cmd.Flags().StringVar(&o.FromPod, "from-pod", o.FromPod, "Copy the pod template from an existing pod, wrap it in a CronJob and run it on the given --schedule. Mutually exclusive with --from-deployment and --image.")
+	cmd.Flags().StringVar(&o.FromDeployment, "from-deployment", o.FromDeployment, "Copy the pod template from an existing deployment, wrap it in a CronJob and run it on the given --schedule. Mutually exclusive with --from-pod and --image.")
+	cmd.Flags().BoolVar(&o.ValidateSchedule, "validate-schedule", o.ValidateSchedule, "If true, parse --schedule locally (standard 5-field cron, @hourly/@daily/@weekly/@monthly/@yearly, or @every <duration>) and reject it before contacting the server.")
+	cmd.Flags().IntVar(&o.PreviewNext, "preview-next", o.PreviewNext, "Print this many upcoming firing times for --schedule to stderr, respecting --timezone, so you can sanity-check the schedule.")
+	cmd.Flags().StringArrayVar(&o.Env, "env", o.Env, "An environment variable to set in the main container, in the form KEY=VALUE. Can be repeated.")
+	cmd.Flags().StringArrayVar(&o.EnvFrom, "env-from", o.EnvFrom, "Populate the main container's environment from a ConfigMap or Secret, in the form configmap/NAME or secret/NAME. Can be repeated.")
+	cmd.Flags().StringArrayVar(&o.Volumes, "volume", o.Volumes, "A volume to make available to every container, in the form NAME=configmap:REF, NAME=secret:REF or NAME=emptyDir. Can be repeated.")
+	cmd.Flags().StringArrayVar(&o.Mounts, "mount", o.Mounts, "Mount a --volume into every container, in the form NAME=/mount/path. Can be repeated.")
+	cmd.Flags().StringVar(&o.ServiceAccount, "service-account", o.ServiceAccount, "Service account to run the job's pods as.")
+	cmd.Flags().StringArrayVar(&o.NodeSelector, "node-selector", o.NodeSelector, "A node selector constraint on the job's pods, in the form KEY=VALUE. Can be repeated.")
+	cmd.Flags().StringVar(&o.Labels, "labels", o.Labels, "Comma-separated key=value labels to apply to the cronjob and its pods.")
+	cmd.Flags().StringVar(&o.Annotations, "annotations", o.Annotations, "Comma-separated key=value annotations to apply to the cronjob.")
+	cmd.Flags().StringVar(&o.Resources, "resources", o.Resources, "Comma-separated resource requests for the main container, e.g. \"cpu=100m,memory=128Mi\".")
+	cmd.Flags().StringArrayVar(&o.Sidecars, "sidecar", o.Sidecars, "An extra container to run alongside the main one, in the form name=image[:tag][,cmd=command args...]. Can be repeated.")
+	cmd.Flags().StringArrayVar(&o.InitContainers, "init-container", o.InitContainers, "An init container to run before the main container, in the form name=image[:tag][,cmd=command args...]. Can be repeated.")
 
 	return cmd
 }
@@ -116,12 +255,32 @@ func (o *CreateCronJobOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, a
 	if len(o.Restart) == 0 {
 		o.Restart = "OnFailure"
 	}
+	if len(o.ConcurrencyPolicy) == 0 {
+		o.ConcurrencyPolicy = string(batchv1beta1.ForbidConcurrent)
+	}
 
 	clientConfig, err := f.ToRESTConfig()
 	if err != nil {
 		return err
 	}
-	o.Client, err = batchv1beta1client.NewForConfig(clientConfig)
+	o.ClientV1, err = batchv1client.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+	o.ClientV1beta1, err = batchv1beta1client.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+	o.ClientV2alpha1, err = batchv2alpha1client.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	discoveryClient, err := f.ToDiscoveryClient()
+	if err != nil {
+		return err
+	}
+	o.NegotiatedVersion, err = negotiateCronJobAPIVersion(discoveryClient, o.APIVersion)
 	if err != nil {
 		return err
 	}
@@ -148,58 +307,552 @@ func (o *CreateCronJobOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, a
 	return nil
 }
 
+// scheduleWithoutTZPrefix strips a leading "TZ=<name> " or "CRON_TZ=<name> "
+// from schedule, if present, leaving the 5-field (or macro) schedule that the
+// cron package knows how to parse.
+func scheduleWithoutTZPrefix(schedule string) string {
+	if !strings.HasPrefix(schedule, "TZ=") && !strings.HasPrefix(schedule, "CRON_TZ=") {
+		return schedule
+	}
+	if idx := strings.IndexByte(schedule, ' '); idx >= 0 {
+		return schedule[idx+1:]
+	}
+	return schedule
+}
+
 func (o *CreateCronJobOptions) Validate() error {
-	if len(o.Image) == 0 {
+	if len(o.FromPod) > 0 && len(o.FromDeployment) > 0 {
+		return fmt.Errorf("--from-pod and --from-deployment are mutually exclusive")
+	}
+	fromWorkload := len(o.FromPod) > 0 || len(o.FromDeployment) > 0
+	if fromWorkload {
+		if len(o.Image) > 0 {
+			return fmt.Errorf("--image cannot be used together with --from-pod or --from-deployment")
+		}
+		if len(o.Env) > 0 || len(o.EnvFrom) > 0 || len(o.Volumes) > 0 || len(o.Mounts) > 0 ||
+			len(o.Resources) > 0 || len(o.Sidecars) > 0 || len(o.InitContainers) > 0 {
+			return fmt.Errorf("--env, --env-from, --volume, --mount, --resources, --sidecar and --init-container describe the main container template and cannot be used together with --from-pod or --from-deployment")
+		}
+		if len(o.Command) > 0 {
+			return fmt.Errorf("a trailing COMMAND cannot be used together with --from-pod or --from-deployment; the fetched workload's command is used as-is")
+		}
+		if o.Cmd.Flags().Changed("restart") {
+			return fmt.Errorf("--restart cannot be used together with --from-pod or --from-deployment; the fetched workload's restart policy is adapted automatically")
+		}
+	} else if len(o.Image) == 0 {
 		return fmt.Errorf("--image must be specified")
 	}
+	if _, err := parseEnvVars(o.Env); err != nil {
+		return fmt.Errorf("--env: %v", err)
+	}
+	if _, err := parseEnvFromSources(o.EnvFrom); err != nil {
+		return fmt.Errorf("--env-from: %v", err)
+	}
+	if _, _, err := parseVolumesAndMounts(o.Volumes, o.Mounts); err != nil {
+		return err
+	}
+	if len(o.Resources) > 0 {
+		if _, err := parseResourceList(o.Resources); err != nil {
+			return fmt.Errorf("--resources: %v", err)
+		}
+	}
+	if _, err := parseContainerSpecs(o.Sidecars); err != nil {
+		return fmt.Errorf("--sidecar: %v", err)
+	}
+	if _, err := parseContainerSpecs(o.InitContainers); err != nil {
+		return fmt.Errorf("--init-container: %v", err)
+	}
+	if len(o.NodeSelector) > 0 {
+		if _, err := parseKeyValueSlice(o.NodeSelector); err != nil {
+			return fmt.Errorf("--node-selector: %v", err)
+		}
+	}
+	if _, err := parseKeyValueCSV(o.Labels); err != nil {
+		return fmt.Errorf("--labels: %v", err)
+	}
+	if _, err := parseKeyValueCSV(o.Annotations); err != nil {
+		return fmt.Errorf("--annotations: %v", err)
+	}
 	if len(o.Schedule) == 0 {
 		return fmt.Errorf("--schedule must be specified")
 	}
+	if !concurrencyPolicies.Has(o.ConcurrencyPolicy) {
+		return fmt.Errorf("invalid --concurrency-policy: %s, supported values: Allow, Forbid, Replace", o.ConcurrencyPolicy)
+	}
+	hasEmbeddedTZ := strings.HasPrefix(o.Schedule, "TZ=") || strings.HasPrefix(o.Schedule, "CRON_TZ=")
+	if len(o.TimeZone) > 0 && hasEmbeddedTZ {
+		return fmt.Errorf("--timezone cannot be used with a TZ= or CRON_TZ= prefix embedded in --schedule")
+	}
+	// Only batch/v1's CronJobSpec carries spec.timeZone (it was added well
+	// after batch/v1beta1 and batch/v2alpha1 were frozen ahead of removal);
+	// it rejects a TZ=/CRON_TZ= prefix server-side, so catch that locally
+	// instead of round-tripping to the apiserver for the rejection.
+	if hasEmbeddedTZ && o.NegotiatedVersion == batchv1.SchemeGroupVersion {
+		return fmt.Errorf("the negotiated CronJob API version (%s) supports spec.timeZone natively and rejects a TZ=/CRON_TZ= prefix in --schedule; use --timezone instead", o.NegotiatedVersion)
+	}
+	if o.ValidateSchedule {
+		if _, err := cron.ParseSchedule(scheduleWithoutTZPrefix(o.Schedule)); err != nil {
+			return fmt.Errorf("invalid --schedule: %v", err)
+		}
+	}
+	if o.PreviewNext < 0 {
+		return fmt.Errorf("--preview-next must not be negative")
+	}
+	if o.Cmd.Flags().Changed("successful-jobs-history-limit") && o.SuccessfulJobsHistoryLimit < 0 {
+		return fmt.Errorf("--successful-jobs-history-limit must not be negative")
+	}
+	if o.Cmd.Flags().Changed("failed-jobs-history-limit") && o.FailedJobsHistoryLimit < 0 {
+		return fmt.Errorf("--failed-jobs-history-limit must not be negative")
+	}
 	return nil
 }
 
 func (o *CreateCronJobOptions) Run() error {
-	var cronjob *batchv1beta1.CronJob
-	cronjob = o.createCronJob()
+	if o.PreviewNext > 0 {
+		if err := o.previewNextSchedules(); err != nil {
+			return err
+		}
+	}
+
+	obj, err := o.createCronJob()
+	if err != nil {
+		return err
+	}
 
 	if !o.DryRun {
-		var err error
-		cronjob, err = o.Client.CronJobs(o.Namespace).Create(cronjob)
+		switch o.NegotiatedVersion {
+		case batchv1.SchemeGroupVersion:
+			obj, err = o.ClientV1.CronJobs(o.Namespace).Create(obj.(*batchv1.CronJob))
+		case batchv1beta1.SchemeGroupVersion:
+			obj, err = o.ClientV1beta1.CronJobs(o.Namespace).Create(obj.(*batchv1beta1.CronJob))
+		case batchv2alpha1.SchemeGroupVersion:
+			obj, err = o.ClientV2alpha1.CronJobs(o.Namespace).Create(obj.(*batchv2alpha1.CronJob))
+		default:
+			err = fmt.Errorf("unsupported negotiated CronJob API version: %s", o.NegotiatedVersion)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to create cronjob: %v", err)
 		}
 	}
 
-	return o.PrintObj(cronjob)
+	return o.PrintObj(obj.(runtime.Object))
 }
 
-func (o *CreateCronJobOptions) createCronJob() *batchv1beta1.CronJob {
-	return &batchv1beta1.CronJob{
-		TypeMeta: metav1.TypeMeta{APIVersion: batchv1beta1.SchemeGroupVersion.String(), Kind: "CronJob"},
-		ObjectMeta: metav1.ObjectMeta{
-			Name: o.Name,
-		},
-		Spec: batchv1beta1.CronJobSpec{
-			Schedule: o.Schedule,
-			JobTemplate: batchv1beta1.JobTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: o.Name,
-				},
-				Spec: batchv1.JobSpec{
-					Template: corev1.PodTemplateSpec{
-						Spec: corev1.PodSpec{
-							Containers: []corev1.Container{
-								{
-									Name:    o.Name,
-									Image:   o.Image,
-									Command: o.Command,
-								},
-							},
-							RestartPolicy: corev1.RestartPolicy(o.Restart),
-						},
-					},
-				},
-			},
+// previewNextSchedules prints the next --preview-next firing times for
+// --schedule to stderr, in the --timezone location if one was given, so users
+// can sanity-check e.g. "*/5 * * * *" vs "5 * * * *" before creating anything.
+func (o *CreateCronJobOptions) previewNextSchedules() error {
+	loc := time.Local
+	if len(o.TimeZone) > 0 {
+		var err error
+		loc, err = time.LoadLocation(o.TimeZone)
+		if err != nil {
+			return fmt.Errorf("invalid --timezone %q: %v", o.TimeZone, err)
+		}
+	}
+	sched, err := cron.ParseSchedule(scheduleWithoutTZPrefix(o.Schedule))
+	if err != nil {
+		return fmt.Errorf("invalid --schedule: %v", err)
+	}
+
+	fmt.Fprintf(o.ErrOut, "Next %d run(s) of schedule %q:\n", o.PreviewNext, o.Schedule)
+	t := time.Now().In(loc)
+	for i := 0; i < o.PreviewNext; i++ {
+		t = sched.Next(t)
+		fmt.Fprintf(o.ErrOut, "  %s\n", t.Format(time.RFC1123))
+	}
+	return nil
+}
+
+// podSpecForJob returns the PodSpec to embed in the CronJob's job template.
+// Normally this is assembled from --image/--command/--restart plus the
+// template-driven flags (--env, --volume, --sidecar, ...), but when
+// --from-pod or --from-deployment is set the whole PodSpec is copied from the
+// referenced workload instead.
+func (o *CreateCronJobOptions) podSpecForJob() (corev1.PodSpec, error) {
+	var podSpec corev1.PodSpec
+	if len(o.FromPod) == 0 && len(o.FromDeployment) == 0 {
+		spec, err := o.templatedPodSpec()
+		if err != nil {
+			return corev1.PodSpec{}, err
+		}
+		podSpec = spec
+	} else {
+		spec, err := o.fetchWorkloadPodSpec()
+		if err != nil {
+			return corev1.PodSpec{}, err
+		}
+		podSpec = spec
+	}
+
+	if len(o.ServiceAccount) > 0 {
+		podSpec.ServiceAccountName = o.ServiceAccount
+	}
+	if len(o.NodeSelector) > 0 {
+		nodeSelector, err := parseKeyValueSlice(o.NodeSelector)
+		if err != nil {
+			return corev1.PodSpec{}, fmt.Errorf("--node-selector: %v", err)
+		}
+		podSpec.NodeSelector = nodeSelector
+	}
+	return podSpec, nil
+}
+
+// fetchWorkloadPodSpec implements --from-pod/--from-deployment by fetching
+// the referenced object and extracting its PodSpec.
+func (o *CreateCronJobOptions) fetchWorkloadPodSpec() (corev1.PodSpec, error) {
+	resourceType, name := "pods", o.FromPod
+	if len(o.FromDeployment) > 0 {
+		resourceType, name = "deployments", o.FromDeployment
+	}
+
+	obj, err := o.Builder.
+		WithScheme(scheme.Scheme, scheme.Scheme.PrioritizedVersionsAllGroups()...).
+		NamespaceParam(o.Namespace).DefaultNamespace().
+		ResourceNames(resourceType, name).
+		Do().
+		Object()
+	if err != nil {
+		return corev1.PodSpec{}, fmt.Errorf("failed to fetch --from-%s %s: %v", strings.TrimSuffix(resourceType, "s"), name, err)
+	}
+
+	var podSpec corev1.PodSpec
+	switch t := obj.(type) {
+	case *corev1.Pod:
+		podSpec = t.Spec
+	case *appsv1.Deployment:
+		podSpec = t.Spec.Template.Spec
+	default:
+		return corev1.PodSpec{}, fmt.Errorf("unsupported object type %T for --from-%s", obj, strings.TrimSuffix(resourceType, "s"))
+	}
+
+	// Jobs reject RestartPolicyAlways, which is what a Deployment's pod
+	// template normally carries; fall back to the Job-compatible default.
+	if podSpec.RestartPolicy == corev1.RestartPolicyAlways {
+		podSpec.RestartPolicy = corev1.RestartPolicyOnFailure
+	}
+	return podSpec, nil
+}
+
+// templatedPodSpec builds a PodSpec from --image/--command/--restart plus the
+// template-driven flags: --env, --env-from, --volume/--mount, --resources,
+// --sidecar and --init-container.
+func (o *CreateCronJobOptions) templatedPodSpec() (corev1.PodSpec, error) {
+	env, err := parseEnvVars(o.Env)
+	if err != nil {
+		return corev1.PodSpec{}, fmt.Errorf("--env: %v", err)
+	}
+	envFrom, err := parseEnvFromSources(o.EnvFrom)
+	if err != nil {
+		return corev1.PodSpec{}, fmt.Errorf("--env-from: %v", err)
+	}
+	var resources corev1.ResourceRequirements
+	if len(o.Resources) > 0 {
+		requests, err := parseResourceList(o.Resources)
+		if err != nil {
+			return corev1.PodSpec{}, fmt.Errorf("--resources: %v", err)
+		}
+		resources.Requests = requests
+	}
+	volumes, mounts, err := parseVolumesAndMounts(o.Volumes, o.Mounts)
+	if err != nil {
+		return corev1.PodSpec{}, err
+	}
+
+	main := corev1.Container{
+		Name:         o.Name,
+		Image:        o.Image,
+		Command:      o.Command,
+		Env:          env,
+		EnvFrom:      envFrom,
+		Resources:    resources,
+		VolumeMounts: mounts,
+	}
+
+	sidecars, err := parseContainerSpecs(o.Sidecars)
+	if err != nil {
+		return corev1.PodSpec{}, fmt.Errorf("--sidecar: %v", err)
+	}
+	for i := range sidecars {
+		sidecars[i].VolumeMounts = append(sidecars[i].VolumeMounts, mounts...)
+	}
+
+	initContainers, err := parseContainerSpecs(o.InitContainers)
+	if err != nil {
+		return corev1.PodSpec{}, fmt.Errorf("--init-container: %v", err)
+	}
+	for i := range initContainers {
+		initContainers[i].VolumeMounts = append(initContainers[i].VolumeMounts, mounts...)
+	}
+
+	return corev1.PodSpec{
+		Containers:     append([]corev1.Container{main}, sidecars...),
+		InitContainers: initContainers,
+		Volumes:        volumes,
+		RestartPolicy:  corev1.RestartPolicy(o.Restart),
+	}, nil
+}
+
+// parseEnvVars parses repeated --env KEY=VALUE flags.
+func parseEnvVars(raw []string) ([]corev1.EnvVar, error) {
+	var env []corev1.EnvVar
+	for _, e := range raw {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("invalid KEY=VALUE pair %q", e)
+		}
+		env = append(env, corev1.EnvVar{Name: parts[0], Value: parts[1]})
+	}
+	return env, nil
+}
+
+// parseEnvFromSources parses repeated --env-from configmap/NAME or
+// secret/NAME flags.
+func parseEnvFromSources(raw []string) ([]corev1.EnvFromSource, error) {
+	var envFrom []corev1.EnvFromSource
+	for _, e := range raw {
+		kind, name, ok := splitPrefixedRef(e)
+		if !ok {
+			return nil, fmt.Errorf("invalid --env-from %q, expected configmap/NAME or secret/NAME", e)
+		}
+		switch kind {
+		case "configmap":
+			envFrom = append(envFrom, corev1.EnvFromSource{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}}})
+		case "secret":
+			envFrom = append(envFrom, corev1.EnvFromSource{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}}})
+		default:
+			return nil, fmt.Errorf("invalid --env-from %q, expected configmap/NAME or secret/NAME", e)
+		}
+	}
+	return envFrom, nil
+}
+
+// splitPrefixedRef splits a "kind/name" reference such as those accepted by
+// --env-from and --volume.
+func splitPrefixedRef(s string) (kind, name string, ok bool) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// parseVolumesAndMounts parses repeated --volume NAME=TYPE:REF (or
+// NAME=emptyDir) and --mount NAME=/path flags into Volumes and the
+// VolumeMounts to attach to every container.
+func parseVolumesAndMounts(rawVolumes, rawMounts []string) ([]corev1.Volume, []corev1.VolumeMount, error) {
+	var volumes []corev1.Volume
+	for _, v := range rawVolumes {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, nil, fmt.Errorf("invalid --volume %q, expected NAME=configmap:REF, NAME=secret:REF or NAME=emptyDir", v)
+		}
+		name, source := parts[0], parts[1]
+		volume := corev1.Volume{Name: name}
+		if source == "emptyDir" {
+			volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+		} else if kind, ref, ok := splitPrefixedRef(strings.Replace(source, ":", "/", 1)); ok {
+			switch kind {
+			case "configmap":
+				volume.ConfigMap = &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: ref}}
+			case "secret":
+				volume.Secret = &corev1.SecretVolumeSource{SecretName: ref}
+			default:
+				return nil, nil, fmt.Errorf("invalid --volume %q, expected NAME=configmap:REF, NAME=secret:REF or NAME=emptyDir", v)
+			}
+		} else {
+			return nil, nil, fmt.Errorf("invalid --volume %q, expected NAME=configmap:REF, NAME=secret:REF or NAME=emptyDir", v)
+		}
+		volumes = append(volumes, volume)
+	}
+
+	var mounts []corev1.VolumeMount
+	for _, m := range rawMounts {
+		parts := strings.SplitN(m, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, nil, fmt.Errorf("invalid --mount %q, expected NAME=/mount/path", m)
+		}
+		mounts = append(mounts, corev1.VolumeMount{Name: parts[0], MountPath: parts[1]})
+	}
+	return volumes, mounts, nil
+}
+
+// parseResourceList parses a comma-separated "cpu=100m,memory=128Mi" string
+// into a ResourceList suitable for container resource requests.
+func parseResourceList(raw string) (corev1.ResourceList, error) {
+	list := corev1.ResourceList{}
+	for _, pair := range strings.Split(raw, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("invalid resource %q, expected NAME=QUANTITY", pair)
+		}
+		quantity, err := apiresource.ParseQuantity(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity for %s: %v", parts[0], err)
+		}
+		list[corev1.ResourceName(parts[0])] = quantity
+	}
+	return list, nil
+}
+
+// parseKeyValueSlice parses repeated KEY=VALUE flags (e.g. --node-selector)
+// into a map.
+func parseKeyValueSlice(raw []string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, kv := range raw {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("invalid KEY=VALUE pair %q", kv)
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
+
+// parseKeyValueCSV parses a comma-separated "key=value,key2=value2" string
+// (e.g. --labels, --annotations) into a map.
+func parseKeyValueCSV(raw string) (map[string]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return parseKeyValueSlice(strings.Split(raw, ","))
+}
+
+// parseContainerSpecs parses repeated --sidecar/--init-container flags in the
+// form name=image[:tag][,cmd=command args...].
+func parseContainerSpecs(raw []string) ([]corev1.Container, error) {
+	var containers []corev1.Container
+	for _, spec := range raw {
+		nameAndRest := strings.SplitN(spec, "=", 2)
+		if len(nameAndRest) != 2 || len(nameAndRest[0]) == 0 {
+			return nil, fmt.Errorf("invalid container spec %q, expected name=image[:tag][,cmd=command args...]", spec)
+		}
+		fields := strings.SplitN(nameAndRest[1], ",cmd=", 2)
+		container := corev1.Container{Name: nameAndRest[0], Image: fields[0]}
+		if len(fields) == 2 {
+			container.Command = strings.Fields(fields[1])
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// createCronJob assembles the CronJob in the group version negotiated in
+// Complete, returning it as an interface{} so callers can type-assert to the
+// concrete *batchv1.CronJob, *batchv1beta1.CronJob or *batchv2alpha1.CronJob.
+func (o *CreateCronJobOptions) createCronJob() (interface{}, error) {
+	podSpec, err := o.podSpecForJob()
+	if err != nil {
+		return nil, err
+	}
+	labels, err := parseKeyValueCSV(o.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("--labels: %v", err)
+	}
+	annotations, err := parseKeyValueCSV(o.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("--annotations: %v", err)
+	}
+
+	jobSpec := batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec:       podSpec,
 		},
 	}
+	if o.Cmd.Flags().Changed("backoff-limit") {
+		jobSpec.BackoffLimit = &o.BackoffLimit
+	}
+	if o.Cmd.Flags().Changed("active-deadline-seconds") {
+		jobSpec.ActiveDeadlineSeconds = &o.ActiveDeadlineSeconds
+	}
+	if o.Cmd.Flags().Changed("parallelism") {
+		jobSpec.Parallelism = &o.Parallelism
+	}
+	if o.Cmd.Flags().Changed("completions") {
+		jobSpec.Completions = &o.Completions
+	}
+
+	objectMeta := metav1.ObjectMeta{Name: o.Name, Labels: labels, Annotations: annotations}
+	// The job template only inherits --labels, not --annotations: annotations
+	// are documented as applying to the CronJob itself, and propagating them
+	// to every spawned Job would be surprising (e.g. annotations that are
+	// only meaningful on the CronJob object).
+	jobTemplateMeta := metav1.ObjectMeta{Labels: labels}
+	typeMeta := metav1.TypeMeta{APIVersion: o.NegotiatedVersion.String(), Kind: "CronJob"}
+
+	// Only batch/v1's CronJobSpec has spec.timeZone; batch/v1beta1 and
+	// batch/v2alpha1 predate it, so the only way to pin a time zone there is
+	// the CRON_TZ= prefix the cron parser understands.
+	schedule := o.Schedule
+	if len(o.TimeZone) > 0 && o.NegotiatedVersion != batchv1.SchemeGroupVersion {
+		schedule = fmt.Sprintf("CRON_TZ=%s %s", o.TimeZone, o.Schedule)
+	}
+
+	switch o.NegotiatedVersion {
+	case batchv1beta1.SchemeGroupVersion:
+		spec := batchv1beta1.CronJobSpec{
+			Schedule:          schedule,
+			ConcurrencyPolicy: batchv1beta1.ConcurrencyPolicy(o.ConcurrencyPolicy),
+			JobTemplate:       batchv1beta1.JobTemplateSpec{ObjectMeta: jobTemplateMeta, Spec: jobSpec},
+		}
+		if o.Cmd.Flags().Changed("suspend") {
+			spec.Suspend = &o.Suspend
+		}
+		if o.Cmd.Flags().Changed("starting-deadline-seconds") {
+			spec.StartingDeadlineSeconds = &o.StartingDeadlineSeconds
+		}
+		if o.Cmd.Flags().Changed("successful-jobs-history-limit") {
+			spec.SuccessfulJobsHistoryLimit = &o.SuccessfulJobsHistoryLimit
+		}
+		if o.Cmd.Flags().Changed("failed-jobs-history-limit") {
+			spec.FailedJobsHistoryLimit = &o.FailedJobsHistoryLimit
+		}
+		// batch/v1beta1.CronJobSpec has no TimeZone field; --timezone is
+		// applied above as a CRON_TZ= prefix on schedule instead.
+		return &batchv1beta1.CronJob{TypeMeta: typeMeta, ObjectMeta: objectMeta, Spec: spec}, nil
+
+	case batchv2alpha1.SchemeGroupVersion:
+		spec := batchv2alpha1.CronJobSpec{
+			Schedule:          schedule,
+			ConcurrencyPolicy: batchv2alpha1.ConcurrencyPolicy(o.ConcurrencyPolicy),
+			JobTemplate:       batchv2alpha1.JobTemplateSpec{ObjectMeta: jobTemplateMeta, Spec: jobSpec},
+		}
+		if o.Cmd.Flags().Changed("suspend") {
+			spec.Suspend = &o.Suspend
+		}
+		if o.Cmd.Flags().Changed("starting-deadline-seconds") {
+			spec.StartingDeadlineSeconds = &o.StartingDeadlineSeconds
+		}
+		if o.Cmd.Flags().Changed("successful-jobs-history-limit") {
+			spec.SuccessfulJobsHistoryLimit = &o.SuccessfulJobsHistoryLimit
+		}
+		if o.Cmd.Flags().Changed("failed-jobs-history-limit") {
+			spec.FailedJobsHistoryLimit = &o.FailedJobsHistoryLimit
+		}
+		return &batchv2alpha1.CronJob{TypeMeta: typeMeta, ObjectMeta: objectMeta, Spec: spec}, nil
+
+	default: // batchv1.SchemeGroupVersion
+		spec := batchv1.CronJobSpec{
+			Schedule:          schedule,
+			ConcurrencyPolicy: batchv1.ConcurrencyPolicy(o.ConcurrencyPolicy),
+			JobTemplate:       batchv1.JobTemplateSpec{ObjectMeta: jobTemplateMeta, Spec: jobSpec},
+		}
+		if o.Cmd.Flags().Changed("suspend") {
+			spec.Suspend = &o.Suspend
+		}
+		if o.Cmd.Flags().Changed("starting-deadline-seconds") {
+			spec.StartingDeadlineSeconds = &o.StartingDeadlineSeconds
+		}
+		if o.Cmd.Flags().Changed("successful-jobs-history-limit") {
+			spec.SuccessfulJobsHistoryLimit = &o.SuccessfulJobsHistoryLimit
+		}
+		if o.Cmd.Flags().Changed("failed-jobs-history-limit") {
+			spec.FailedJobsHistoryLimit = &o.FailedJobsHistoryLimit
+		}
+		if len(o.TimeZone) > 0 {
+			spec.TimeZone = &o.TimeZone
+		}
+		return &batchv1.CronJob{TypeMeta: typeMeta, ObjectMeta: objectMeta, Spec: spec}, nil
+	}
 }
@@ -0,0 +1,428 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	batchv2alpha1 "k8s.io/api/batch/v2alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+)
+
+// fakeDiscoveryClient is a minimal discovery.DiscoveryInterface stub that
+// only implements ServerGroups; embedding the interface satisfies the rest
+// of its (large) method set without a real implementation.
+type fakeDiscoveryClient struct {
+	discovery.DiscoveryInterface
+	groups *metav1.APIGroupList
+	err    error
+}
+
+func (f *fakeDiscoveryClient) ServerGroups() (*metav1.APIGroupList, error) {
+	return f.groups, f.err
+}
+
+func apiGroupList(group string, versions ...string) *metav1.APIGroupList {
+	g := metav1.APIGroup{Name: group}
+	for _, v := range versions {
+		g.Versions = append(g.Versions, metav1.GroupVersionForDiscovery{
+			GroupVersion: group + "/" + v,
+			Version:      v,
+		})
+	}
+	return &metav1.APIGroupList{Groups: []metav1.APIGroup{g}}
+}
+
+func TestNegotiateCronJobAPIVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		groups     *metav1.APIGroupList
+		discoErr   error
+		want       schema.GroupVersion
+		wantErr    bool
+	}{
+		{
+			name:   "prefers v1 when both v1 and v1beta1 are served",
+			groups: apiGroupList("batch", "v1beta1", "v1"),
+			want:   batchv1.SchemeGroupVersion,
+		},
+		{
+			name:   "falls back to v1beta1 when v1 is not served",
+			groups: apiGroupList("batch", "v1beta1", "v2alpha1"),
+			want:   batchv1beta1.SchemeGroupVersion,
+		},
+		{
+			name:   "falls back to v2alpha1 when nothing else is served",
+			groups: apiGroupList("batch", "v2alpha1"),
+			want:   batchv2alpha1.SchemeGroupVersion,
+		},
+		{
+			name:    "errors when the batch group serves no known CronJob version",
+			groups:  apiGroupList("batch", "v3"),
+			wantErr: true,
+		},
+		{
+			name:     "errors when discovery fails",
+			discoErr: fmt.Errorf("boom"),
+			wantErr:  true,
+		},
+		{
+			name:       "--api-version forces the version without consulting discovery",
+			apiVersion: "batch/v1beta1",
+			want:       batchv1beta1.SchemeGroupVersion,
+		},
+		{
+			name:       "--api-version rejects an unsupported group/version",
+			apiVersion: "batch/v3",
+			wantErr:    true,
+		},
+		{
+			name:       "--api-version rejects a malformed value",
+			apiVersion: "not a group version",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeDiscoveryClient{groups: tt.groups, err: tt.discoErr}
+			got, err := negotiateCronJobAPIVersion(client, tt.apiVersion)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("negotiateCronJobAPIVersion() expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("negotiateCronJobAPIVersion() returned unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("negotiateCronJobAPIVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newValidateTestOptions returns CreateCronJobOptions with just enough of a
+// *cobra.Command wired up for Validate's Flags().Changed checks, defaulted
+// to an otherwise-minimal-but-valid set of options.
+func newValidateTestOptions() *CreateCronJobOptions {
+	o := NewCreateCronJobOptions(genericclioptions.IOStreams{})
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&o.Restart, "restart", "OnFailure", "")
+	cmd.Flags().Int32Var(&o.SuccessfulJobsHistoryLimit, "successful-jobs-history-limit", 0, "")
+	cmd.Flags().Int32Var(&o.FailedJobsHistoryLimit, "failed-jobs-history-limit", 0, "")
+	o.Cmd = cmd
+	o.Image = "busybox"
+	o.Schedule = "* * * * *"
+	o.ConcurrencyPolicy = string(batchv1beta1.ForbidConcurrent)
+	return o
+}
+
+func TestCreateCronJobOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(o *CreateCronJobOptions)
+		wantErr bool
+	}{
+		{name: "valid minimal options"},
+		{
+			name:    "missing --image",
+			mutate:  func(o *CreateCronJobOptions) { o.Image = "" },
+			wantErr: true,
+		},
+		{
+			name: "--from-pod and --from-deployment are mutually exclusive",
+			mutate: func(o *CreateCronJobOptions) {
+				o.FromPod, o.FromDeployment = "my-pod", "my-deployment"
+			},
+			wantErr: true,
+		},
+		{
+			name: "--from-pod rejects --image",
+			mutate: func(o *CreateCronJobOptions) {
+				o.FromPod = "my-pod"
+			},
+			wantErr: true,
+		},
+		{
+			name: "--from-pod rejects --env",
+			mutate: func(o *CreateCronJobOptions) {
+				o.Image, o.FromPod, o.Env = "", "my-pod", []string{"FOO=bar"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "--from-pod rejects a trailing COMMAND",
+			mutate: func(o *CreateCronJobOptions) {
+				o.Image, o.FromPod, o.Command = "", "my-pod", []string{"echo", "hi"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "--from-pod rejects --restart",
+			mutate: func(o *CreateCronJobOptions) {
+				o.Image, o.FromPod = "", "my-pod"
+				o.Cmd.Flags().Set("restart", "Never")
+			},
+			wantErr: true,
+		},
+		{
+			name: "--from-pod alone is valid",
+			mutate: func(o *CreateCronJobOptions) {
+				o.Image, o.FromPod = "", "my-pod"
+			},
+		},
+		{
+			name:    "missing --schedule",
+			mutate:  func(o *CreateCronJobOptions) { o.Schedule = "" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid --env",
+			mutate:  func(o *CreateCronJobOptions) { o.Env = []string{"NOEQUALS"} },
+			wantErr: true,
+		},
+		{
+			name:    "invalid --concurrency-policy",
+			mutate:  func(o *CreateCronJobOptions) { o.ConcurrencyPolicy = "Sometimes" },
+			wantErr: true,
+		},
+		{
+			name: "--timezone conflicts with an embedded TZ= prefix",
+			mutate: func(o *CreateCronJobOptions) {
+				o.Schedule, o.TimeZone = "TZ=UTC * * * * *", "America/New_York"
+			},
+			wantErr: true,
+		},
+		{
+			name: "embedded CRON_TZ= prefix rejected against batch/v1",
+			mutate: func(o *CreateCronJobOptions) {
+				o.Schedule, o.NegotiatedVersion = "CRON_TZ=UTC * * * * *", batchv1.SchemeGroupVersion
+			},
+			wantErr: true,
+		},
+		{
+			name: "embedded CRON_TZ= prefix accepted against batch/v1beta1",
+			mutate: func(o *CreateCronJobOptions) {
+				o.Schedule, o.NegotiatedVersion = "CRON_TZ=UTC * * * * *", batchv1beta1.SchemeGroupVersion
+			},
+		},
+		{
+			name:    "negative --successful-jobs-history-limit",
+			mutate:  func(o *CreateCronJobOptions) { o.Cmd.Flags().Set("successful-jobs-history-limit", "-1") },
+			wantErr: true,
+		},
+		{
+			name:    "negative --failed-jobs-history-limit",
+			mutate:  func(o *CreateCronJobOptions) { o.Cmd.Flags().Set("failed-jobs-history-limit", "-1") },
+			wantErr: true,
+		},
+		{
+			name: "unset history limits default to zero and are not rejected",
+			mutate: func(o *CreateCronJobOptions) {
+				o.SuccessfulJobsHistoryLimit, o.FailedJobsHistoryLimit = 0, 0
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newValidateTestOptions()
+			if tt.mutate != nil {
+				tt.mutate(o)
+			}
+			err := o.Validate()
+			if tt.wantErr && err == nil {
+				t.Fatal("Validate() expected an error, got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Validate() returned unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseEnvVars(t *testing.T) {
+	tests := []struct {
+		raw     []string
+		want    []corev1.EnvVar
+		wantErr bool
+	}{
+		{raw: nil, want: nil},
+		{raw: []string{"FOO=bar"}, want: []corev1.EnvVar{{Name: "FOO", Value: "bar"}}},
+		{raw: []string{"FOO=bar=baz"}, want: []corev1.EnvVar{{Name: "FOO", Value: "bar=baz"}}},
+		{raw: []string{"FOO="}, want: []corev1.EnvVar{{Name: "FOO", Value: ""}}},
+		{raw: []string{"FOO"}, wantErr: true},
+		{raw: []string{"=bar"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.raw, ","), func(t *testing.T) {
+			got, err := parseEnvVars(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseEnvVars(%v) expected an error, got none", tt.raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEnvVars(%v) returned unexpected error: %v", tt.raw, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseEnvVars(%v) = %v, want %v", tt.raw, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("parseEnvVars(%v)[%d] = %v, want %v", tt.raw, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseEnvFromSources(t *testing.T) {
+	tests := []struct {
+		raw     string
+		wantErr bool
+	}{
+		{raw: "configmap/my-config"},
+		{raw: "secret/my-secret"},
+		{raw: "my-config", wantErr: true},
+		{raw: "deployment/my-config", wantErr: true},
+		{raw: "configmap/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			_, err := parseEnvFromSources([]string{tt.raw})
+			if tt.wantErr && err == nil {
+				t.Fatalf("parseEnvFromSources(%q) expected an error, got none", tt.raw)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("parseEnvFromSources(%q) returned unexpected error: %v", tt.raw, err)
+			}
+		})
+	}
+}
+
+func TestParseVolumesAndMounts(t *testing.T) {
+	volumes, mounts, err := parseVolumesAndMounts(
+		[]string{"cfg=configmap:my-config", "sec=secret:my-secret", "tmp=emptyDir"},
+		[]string{"cfg=/etc/cfg", "sec=/etc/sec"},
+	)
+	if err != nil {
+		t.Fatalf("parseVolumesAndMounts returned unexpected error: %v", err)
+	}
+	if len(volumes) != 3 {
+		t.Fatalf("expected 3 volumes, got %d: %v", len(volumes), volumes)
+	}
+	if volumes[0].ConfigMap == nil || volumes[0].ConfigMap.Name != "my-config" {
+		t.Errorf("volumes[0] = %+v, want a ConfigMap volume named my-config", volumes[0])
+	}
+	if volumes[1].Secret == nil || volumes[1].Secret.SecretName != "my-secret" {
+		t.Errorf("volumes[1] = %+v, want a Secret volume named my-secret", volumes[1])
+	}
+	if volumes[2].EmptyDir == nil {
+		t.Errorf("volumes[2] = %+v, want an EmptyDir volume", volumes[2])
+	}
+	if len(mounts) != 2 || mounts[0].MountPath != "/etc/cfg" || mounts[1].MountPath != "/etc/sec" {
+		t.Errorf("mounts = %+v, want mounts at /etc/cfg and /etc/sec", mounts)
+	}
+
+	for _, tt := range []struct {
+		volumes, mounts []string
+	}{
+		{volumes: []string{"bad"}},
+		{volumes: []string{"cfg=unknown:ref"}},
+		{mounts: []string{"bad"}},
+		{mounts: []string{"name="}},
+	} {
+		if _, _, err := parseVolumesAndMounts(tt.volumes, tt.mounts); err == nil {
+			t.Errorf("parseVolumesAndMounts(%v, %v) expected an error, got none", tt.volumes, tt.mounts)
+		}
+	}
+}
+
+func TestParseResourceList(t *testing.T) {
+	list, err := parseResourceList("cpu=100m,memory=128Mi")
+	if err != nil {
+		t.Fatalf("parseResourceList returned unexpected error: %v", err)
+	}
+	if list.Cpu().String() != "100m" {
+		t.Errorf("cpu = %s, want 100m", list.Cpu().String())
+	}
+	if list.Memory().String() != "128Mi" {
+		t.Errorf("memory = %s, want 128Mi", list.Memory().String())
+	}
+
+	for _, raw := range []string{"cpu", "cpu=", "cpu=not-a-quantity"} {
+		if _, err := parseResourceList(raw); err == nil {
+			t.Errorf("parseResourceList(%q) expected an error, got none", raw)
+		}
+	}
+}
+
+func TestParseContainerSpecs(t *testing.T) {
+	containers, err := parseContainerSpecs([]string{
+		"reload=busybox:1.35",
+		"init=alpine,cmd=sh -c echo hi",
+	})
+	if err != nil {
+		t.Fatalf("parseContainerSpecs returned unexpected error: %v", err)
+	}
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d: %v", len(containers), containers)
+	}
+	if containers[0].Name != "reload" || containers[0].Image != "busybox:1.35" || containers[0].Command != nil {
+		t.Errorf("containers[0] = %+v, want name=reload image=busybox:1.35 with no command", containers[0])
+	}
+	if containers[1].Name != "init" || containers[1].Image != "alpine" {
+		t.Errorf("containers[1] = %+v, want name=init image=alpine", containers[1])
+	}
+	wantCmd := []string{"sh", "-c", "echo", "hi"}
+	if strings.Join(containers[1].Command, " ") != strings.Join(wantCmd, " ") {
+		t.Errorf("containers[1].Command = %v, want %v", containers[1].Command, wantCmd)
+	}
+
+	if _, err := parseContainerSpecs([]string{"noequals"}); err == nil {
+		t.Error("parseContainerSpecs([\"noequals\"]) expected an error, got none")
+	}
+}
+
+func TestParseKeyValueCSV(t *testing.T) {
+	got, err := parseKeyValueCSV("a=1,b=2")
+	if err != nil {
+		t.Fatalf("parseKeyValueCSV returned unexpected error: %v", err)
+	}
+	if got["a"] != "1" || got["b"] != "2" {
+		t.Errorf("parseKeyValueCSV(\"a=1,b=2\") = %v, want map[a:1 b:2]", got)
+	}
+	if got, err := parseKeyValueCSV(""); err != nil || got != nil {
+		t.Errorf("parseKeyValueCSV(\"\") = (%v, %v), want (nil, nil)", got, err)
+	}
+	if _, err := parseKeyValueCSV("noequals"); err == nil {
+		t.Error("parseKeyValueCSV(\"noequals\") expected an error, got none")
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cron implements a small, self-contained parser for the schedule
+// strings accepted by the CronJob API: the standard 5-field cron format, the
+// `@hourly`/`@daily`/`@weekly`/`@monthly`/`@yearly` macros, and `@every
+// <duration>`. It exists so `kubectl create cronjob` can catch an obviously
+// broken schedule before round-tripping to the apiserver.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field bounds, in (min, max) order, for the five standard cron fields.
+var (
+	minuteBounds = bounds{0, 59}
+	hourBounds   = bounds{0, 23}
+	domBounds    = bounds{1, 31}
+	monthBounds  = bounds{1, 12}
+	dowBounds    = bounds{0, 7}
+)
+
+type bounds struct {
+	min, max uint
+}
+
+// namedSchedules maps the cron macros to their 5-field equivalent.
+var namedSchedules = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule is a parsed cron expression. Use Next to find successive
+// activation times.
+type Schedule struct {
+	minutes, hours, doms, months, dows bitset
+
+	// domRestricted and dowRestricted record whether the day-of-month and
+	// day-of-week fields, respectively, were anything other than "*". Per
+	// standard cron semantics, when both are restricted a day matches if it
+	// satisfies *either* field, not both; see Next.
+	domRestricted, dowRestricted bool
+
+	// every holds the interval for an "@every <duration>" schedule; it is
+	// zero for standard 5-field schedules.
+	every time.Duration
+}
+
+// bitset is a bitmask over a cron field's values, indexed directly by value
+// (e.g. bit 5 set means "5" is one of the field's allowed values).
+type bitset uint64
+
+func (b bitset) has(v uint) bool { return b&(1<<v) != 0 }
+
+// ParseSchedule parses a cron schedule: the standard 5-field format, one of
+// the @hourly/@daily/@weekly/@monthly/@yearly/@annually/@midnight macros, or
+// "@every <duration>" (duration in time.ParseDuration syntax, e.g. "1h30m").
+func ParseSchedule(schedule string) (*Schedule, error) {
+	schedule = strings.TrimSpace(schedule)
+	if schedule == "" {
+		return nil, fmt.Errorf("schedule must not be empty")
+	}
+
+	if strings.HasPrefix(schedule, "@every ") {
+		every, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(schedule, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid @every duration: %v", err)
+		}
+		if every <= 0 {
+			return nil, fmt.Errorf("@every duration must be positive")
+		}
+		return &Schedule{every: every}, nil
+	}
+
+	if expanded, ok := namedSchedules[schedule]; ok {
+		schedule = expanded
+	} else if strings.HasPrefix(schedule, "@") {
+		return nil, fmt.Errorf("unrecognized schedule macro %q, supported values: @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly, @every <duration>", schedule)
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 space-separated fields (minute hour day-of-month month day-of-week), got %d in %q", len(fields), schedule)
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minutes, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, fmt.Errorf("minute field: %v", err)
+	}
+	if s.hours, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, fmt.Errorf("hour field: %v", err)
+	}
+	if s.doms, err = parseField(fields[2], domBounds); err != nil {
+		return nil, fmt.Errorf("day-of-month field: %v", err)
+	}
+	if s.months, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, fmt.Errorf("month field: %v", err)
+	}
+	if s.dows, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, fmt.Errorf("day-of-week field: %v", err)
+	}
+	s.domRestricted = fields[2] != "*"
+	s.dowRestricted = fields[4] != "*"
+	return s, nil
+}
+
+// parseField parses a single cron field: "*", "*/step", "a", "a-b", "a-b/step",
+// or a comma-separated list of any of those.
+func parseField(field string, b bounds) (bitset, error) {
+	var set bitset
+	for _, part := range strings.Split(field, ",") {
+		lo, hi, step, err := parseRange(part, b)
+		if err != nil {
+			return 0, err
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << (v % 64)
+		}
+	}
+	// Day-of-week 7 is a common alias for Sunday (0); normalize it.
+	if b == dowBounds && set.has(7) {
+		set |= 1 << 0
+	}
+	return set, nil
+}
+
+func parseRange(part string, b bounds) (lo, hi, step uint, err error) {
+	step = 1
+	rangeAndStep := strings.SplitN(part, "/", 2)
+	if len(rangeAndStep) == 2 {
+		s, err := strconv.ParseUint(rangeAndStep[1], 10, 32)
+		if err != nil || s == 0 {
+			return 0, 0, 0, fmt.Errorf("invalid step in %q", part)
+		}
+		step = uint(s)
+	}
+
+	switch base := rangeAndStep[0]; {
+	case base == "*":
+		lo, hi = b.min, b.max
+	case strings.Contains(base, "-"):
+		bounds := strings.SplitN(base, "-", 2)
+		lo, err = parseValue(bounds[0], b)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		hi, err = parseValue(bounds[1], b)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if lo > hi {
+			return 0, 0, 0, fmt.Errorf("invalid range %q: %d is after %d", base, lo, hi)
+		}
+	default:
+		v, err := parseValue(base, b)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		lo, hi = v, v
+	}
+	return lo, hi, step, nil
+}
+
+func parseValue(s string, b bounds) (uint, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if uint(v) < b.min || uint(v) > b.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, b.min, b.max)
+	}
+	return uint(v), nil
+}
+
+// yearLimit bounds how far into the future Next will search before giving up,
+// guarding against schedules (e.g. Feb 30) that can never match.
+const yearLimit = 5
+
+// Next returns the first activation time strictly after t.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute).In(loc)
+	deadline := t.AddDate(yearLimit, 0, 0)
+
+	for t.Before(deadline) {
+		if !s.months.has(uint(t.Month())) {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, 1, 0)
+			continue
+		}
+		// Per man 5 crontab: if both day-of-month and day-of-week are
+		// restricted (not "*"), a day matches when it satisfies either
+		// field; otherwise the unrestricted field is implicitly satisfied
+		// and this reduces to the usual AND of the two.
+		domMatch, dowMatch := s.doms.has(uint(t.Day())), s.dows.has(uint(t.Weekday()))
+		dayMatches := domMatch && dowMatch
+		if s.domRestricted && s.dowRestricted {
+			dayMatches = domMatch || dowMatch
+		}
+		if !dayMatches {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+			continue
+		}
+		if !s.hours.has(uint(t.Hour())) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(time.Hour)
+			continue
+		}
+		if !s.minutes.has(uint(t.Minute())) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	// Unreachable for any schedule that can legitimately match; returning the
+	// zero value signals "never" to callers that must not loop forever.
+	return time.Time{}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleValid(t *testing.T) {
+	tests := []string{
+		"* * * * *",
+		"*/5 * * * *",
+		"5 * * * *",
+		"0 0 1 1 *",
+		"0 0 * * 0",
+		"0 0 * * 7",
+		"15,45 9-17 * * 1-5",
+		"@hourly",
+		"@daily",
+		"@midnight",
+		"@weekly",
+		"@monthly",
+		"@yearly",
+		"@annually",
+		"@every 1h30m",
+		"@every 90s",
+	}
+	for _, schedule := range tests {
+		t.Run(schedule, func(t *testing.T) {
+			if _, err := ParseSchedule(schedule); err != nil {
+				t.Errorf("ParseSchedule(%q) returned unexpected error: %v", schedule, err)
+			}
+		})
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"@nonsense",
+		"@every -5m",
+		"@every notaduration",
+	}
+	for _, schedule := range tests {
+		t.Run(schedule, func(t *testing.T) {
+			if _, err := ParseSchedule(schedule); err == nil {
+				t.Errorf("ParseSchedule(%q) expected an error, got none", schedule)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	from := time.Date(2023, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		schedule string
+		want     time.Time
+	}{
+		{"* * * * *", time.Date(2023, time.March, 15, 10, 31, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2023, time.March, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 0 * * *", time.Date(2023, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2023, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		// When both day-of-month and day-of-week are restricted, cron
+		// semantics are OR, not AND: Monday the 20th satisfies dow=Mon
+		// and comes before the next midnight on a 1st or 15th (April 1).
+		{"0 0 1,15 * 1", time.Date(2023, time.March, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			s, err := ParseSchedule(tt.schedule)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) returned unexpected error: %v", tt.schedule, err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNextEvery(t *testing.T) {
+	from := time.Date(2023, time.March, 15, 10, 30, 0, 0, time.UTC)
+	s, err := ParseSchedule("@every 10m")
+	if err != nil {
+		t.Fatalf("ParseSchedule returned unexpected error: %v", err)
+	}
+	want := time.Date(2023, time.March, 15, 10, 40, 0, 0, time.UTC)
+	if got := s.Next(from); !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", from, got, want)
+	}
+}